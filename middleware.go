@@ -0,0 +1,302 @@
+package apidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// validateResponsesEnabled gates response-body validation, which is opt-in
+// because it requires buffering every response in memory. Toggle it with
+// EnableResponseValidation during local/dev bootstrap - never in production.
+var validateResponsesEnabled int32
+
+// EnableResponseValidation turns response-body validation on or off for all
+// ValidatingMiddleware instances, including ones already created: the setting
+// is read on every request. It is dev-mode only:
+// the middleware buffers the entire response body to validate it, which is
+// wasted work (and memory) in production.
+func EnableResponseValidation(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&validateResponsesEnabled, v)
+}
+
+// compiledEndpoint pairs an EndpointConfig with the schemas reflected from
+// its request/response types, computed once when the middleware is built.
+type compiledEndpoint struct {
+	endpoint        EndpointConfig
+	pathSegments    []string
+	parameters      []compiledParameter
+	requestSchema   map[string]interface{}
+	responseSchemas map[int]map[string]interface{}
+}
+
+// compiledParameter is a Parameter with its schema already reflected.
+type compiledParameter struct {
+	name     string
+	in       string
+	required bool
+	schema   map[string]interface{}
+}
+
+// compileEndpoints reflects every registered endpoint's request/response
+// types into a shared components map, deduplicated the same way
+// GenerateOpenAPI deduplicates them.
+func compileEndpoints() ([]compiledEndpoint, map[string]map[string]interface{}) {
+	endpoints := GetEndpoints()
+	schemas := make(map[string]map[string]interface{})
+	builder := newSchemaBuilder(schemas)
+
+	compiled := make([]compiledEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		ce := compiledEndpoint{
+			endpoint:     endpoint,
+			pathSegments: strings.Split(strings.Trim(endpoint.Path, "/"), "/"),
+		}
+
+		if endpoint.RequestBody != nil {
+			ce.requestSchema = reflectRequestSchema(endpoint.RequestBody, builder)
+		}
+
+		if len(endpoint.Parameters) > 0 {
+			ce.parameters = make([]compiledParameter, len(endpoint.Parameters))
+			for i, param := range endpoint.Parameters {
+				var schema map[string]interface{}
+				if param.Schema != nil {
+					schema = reflectToJSONSchema(param.Schema, builder)
+				}
+				ce.parameters[i] = compiledParameter{
+					name:     param.Name,
+					in:       param.In,
+					required: param.Required,
+					schema:   schema,
+				}
+			}
+		}
+
+		if len(endpoint.Responses) > 0 {
+			ce.responseSchemas = make(map[int]map[string]interface{})
+			for statusCode, responseType := range endpoint.Responses {
+				if responseType == nil {
+					continue
+				}
+				if _, isDescriptionOnly := responseType.(string); isDescriptionOnly {
+					continue
+				}
+				ce.responseSchemas[statusCode] = reflectToJSONSchema(responseType, builder)
+			}
+		}
+
+		compiled = append(compiled, ce)
+	}
+
+	return compiled, schemas
+}
+
+// matchPath reports whether requestPath matches an endpoint's path template,
+// where template segments wrapped in "{...}" match any single path segment,
+// and returns the values captured by those segments keyed by parameter name.
+func matchPath(segments []string, requestPath string) (bool, map[string]string) {
+	requestSegments := strings.Split(strings.Trim(requestPath, "/"), "/")
+	if len(requestSegments) != len(segments) {
+		return false, nil
+	}
+
+	var params map[string]string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")] = requestSegments[i]
+			continue
+		}
+		if segment != requestSegments[i] {
+			return false, nil
+		}
+	}
+
+	return true, params
+}
+
+func findCompiledEndpoint(compiled []compiledEndpoint, method, path string) (*compiledEndpoint, map[string]string) {
+	for i := range compiled {
+		ce := &compiled[i]
+		if ce.endpoint.Method != method {
+			continue
+		}
+		if ok, params := matchPath(ce.pathSegments, path); ok {
+			return ce, params
+		}
+	}
+	return nil, nil
+}
+
+// responseRecorder buffers a handler's response so it can be validated
+// before being written to the real ResponseWriter.
+type responseRecorder struct {
+	http.ResponseWriter
+	body       bytes.Buffer
+	statusCode int
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+// ValidatingMiddleware wraps next with request (and, if enabled via
+// EnableResponseValidation, response) body validation driven by the JSON
+// schemas reflection produces for each registered EndpointConfig. The
+// registry is snapshotted once, at wrap time, so register every endpoint
+// before calling this.
+func ValidatingMiddleware(next http.Handler) http.Handler {
+	compiled, schemas := compileEndpoints()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint, pathParams := findCompiledEndpoint(compiled, r.Method, r.URL.Path)
+		if endpoint == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Parameter and body violations are aggregated into a single 400 so
+		// callers see the full list of problems with a request, not just
+		// whichever phase happens to run first.
+		violations := validateParameters(endpoint.parameters, r, pathParams)
+
+		if endpoint.requestSchema != nil {
+			bodyViolations, err := validateRequestBody(r, endpoint.requestSchema, schemas)
+			if err != nil {
+				writeValidationErrors(w, http.StatusInternalServerError, ValidationErrors{{Path: "$", Rule: "body", Message: "failed to read request body"}})
+				return
+			}
+			violations = append(violations, bodyViolations...)
+		}
+
+		if len(violations) > 0 {
+			writeValidationErrors(w, http.StatusBadRequest, violations)
+			return
+		}
+
+		if atomic.LoadInt32(&validateResponsesEnabled) == 0 || len(endpoint.responseSchemas) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if schema, ok := endpoint.responseSchemas[rec.statusCode]; ok && rec.body.Len() > 0 {
+			if violations := validateJSONBody(rec.body.Bytes(), schema, schemas, directionResponse); len(violations) > 0 {
+				writeValidationErrors(w, http.StatusInternalServerError, violations)
+				return
+			}
+		}
+
+		w.WriteHeader(rec.statusCode)
+		_, _ = w.Write(rec.body.Bytes())
+	})
+}
+
+// ValidatingHandlerFunc is the http.HandlerFunc equivalent of
+// ValidatingMiddleware, for callers that register handlers directly rather
+// than through a middleware chain.
+func ValidatingHandlerFunc(next http.HandlerFunc) http.HandlerFunc {
+	return ValidatingMiddleware(next).ServeHTTP
+}
+
+// validateParameters checks an endpoint's declared path/query/header/cookie
+// parameters against the live request, using pathParams captured by
+// matchPath for "path" parameters.
+func validateParameters(parameters []compiledParameter, r *http.Request, pathParams map[string]string) ValidationErrors {
+	var errs ValidationErrors
+
+	for _, param := range parameters {
+		value, present := parameterValue(param, r, pathParams)
+		path := "$." + param.in + "." + param.name
+
+		if !present {
+			if param.required {
+				errs = append(errs, ValidationError{Path: path, Rule: "required", Message: "missing required parameter"})
+			}
+			continue
+		}
+
+		errs = append(errs, validateParameterValue(param.schema, value, path)...)
+	}
+
+	return errs
+}
+
+func parameterValue(param compiledParameter, r *http.Request, pathParams map[string]string) (string, bool) {
+	switch param.in {
+	case "path":
+		value, ok := pathParams[param.name]
+		return value, ok
+	case "query":
+		values := r.URL.Query()
+		if !values.Has(param.name) {
+			return "", false
+		}
+		return values.Get(param.name), true
+	case "header":
+		if len(r.Header.Values(param.name)) == 0 {
+			return "", false
+		}
+		return r.Header.Get(param.name), true
+	case "cookie":
+		cookie, err := r.Cookie(param.name)
+		if err != nil {
+			return "", false
+		}
+		return cookie.Value, true
+	default:
+		return "", false
+	}
+}
+
+func validateRequestBody(r *http.Request, schema map[string]interface{}, schemas map[string]map[string]interface{}) (ValidationErrors, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	return validateJSONBody(body, schema, schemas, directionRequest), nil
+}
+
+func validateJSONBody(body []byte, schema map[string]interface{}, schemas map[string]map[string]interface{}, direction string) ValidationErrors {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return ValidationErrors{{Path: "$", Rule: "type", Message: "body is not valid JSON"}}
+	}
+
+	return validateAgainstSchema(schema, schemas, data, "$", direction)
+}
+
+func writeValidationErrors(w http.ResponseWriter, statusCode int, violations ValidationErrors) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":      "validation failed",
+		"violations": violations,
+	})
+}
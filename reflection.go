@@ -6,31 +6,66 @@ import (
 	"strings"
 )
 
-// reflectToJSONSchema converts a Go type to JSON Schema using reflection
-func reflectToJSONSchema(v interface{}) map[string]interface{} {
+// schemaBuilder reflects Go types into JSON Schema while populating a shared
+// components.schemas map. Named struct types are emitted once and referenced
+// via "$ref" everywhere else they occur, so a builder must be reused across
+// every endpoint in a single spec/description for dedup to take effect.
+type schemaBuilder struct {
+	schemas      map[string]map[string]interface{} // component name -> schema body
+	baseNames    map[reflect.Type]string           // go type -> dedup'd component name, shared by both variants below
+	nameOwners   map[string]reflect.Type           // component name -> owning go type (collision detection)
+	fullEmitted  map[reflect.Type]bool             // go type -> full (readOnly-inclusive) component already written
+	inputEmitted map[reflect.Type]bool             // go type -> "...Input" (readOnly-stripped) component already written
+}
+
+// newSchemaBuilder creates a schemaBuilder that writes components into schemas.
+func newSchemaBuilder(schemas map[string]map[string]interface{}) *schemaBuilder {
+	return &schemaBuilder{
+		schemas:      schemas,
+		baseNames:    make(map[reflect.Type]string),
+		nameOwners:   make(map[string]reflect.Type),
+		fullEmitted:  make(map[reflect.Type]bool),
+		inputEmitted: make(map[reflect.Type]bool),
+	}
+}
+
+// reflectToJSONSchema converts a Go type to JSON Schema using reflection,
+// deduplicating named struct types into b's components map. Struct fields
+// tagged `openapi:"readOnly"` are included, which is correct for response
+// bodies; use reflectRequestSchema for request bodies instead.
+func reflectToJSONSchema(v interface{}, b *schemaBuilder) map[string]interface{} {
 	if v == nil {
 		return map[string]interface{}{"type": "object"}
 	}
 
-	t := reflect.TypeOf(v)
+	return b.schemaFor(reflect.TypeOf(v), false)
+}
 
-	// Dereference pointers
-	for t.Kind() == reflect.Ptr {
-		t = t.Elem()
+// reflectRequestSchema is reflectToJSONSchema for request bodies: every named
+// struct type is emitted under a separate "<TypeName>Input" component with
+// `openapi:"readOnly"` fields stripped, since a client can never set them.
+func reflectRequestSchema(v interface{}, b *schemaBuilder) map[string]interface{} {
+	if v == nil {
+		return map[string]interface{}{"type": "object"}
 	}
 
-	return typeToSchema(t)
+	return b.schemaFor(reflect.TypeOf(v), true)
 }
 
-// typeToSchema converts reflect.Type to JSON Schema
-func typeToSchema(t reflect.Type) map[string]interface{} {
+// schemaFor converts reflect.Type to JSON Schema, dereferencing pointers.
+// input selects the readOnly-stripped "...Input" variant for struct types.
+func (b *schemaBuilder) schemaFor(t reflect.Type, input bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
 	switch t.Kind() {
 	case reflect.Struct:
-		return structToSchema(t)
+		return b.structRef(t, input)
 	case reflect.Slice, reflect.Array:
-		return arrayToSchema(t)
+		return b.arraySchema(t, input)
 	case reflect.Map:
-		return mapToSchema(t)
+		return b.mapSchema(t, input)
 	case reflect.String:
 		return map[string]interface{}{"type": "string"}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
@@ -45,14 +80,125 @@ func typeToSchema(t reflect.Type) map[string]interface{} {
 	}
 }
 
-// structToSchema converts a struct to JSON Schema
-func structToSchema(t reflect.Type) map[string]interface{} {
-	schema := map[string]interface{}{
-		"type":       "object",
-		"properties": make(map[string]interface{}),
+// structRef returns a schema for a struct type. Named struct types are
+// registered as a component on first use (before their body is built, so
+// self-referential fields resolve to the same "$ref" instead of recursing
+// forever) and a "$ref" is returned on every use thereafter. Anonymous
+// struct types have no stable name to dedup under, so they stay inline.
+//
+// A type used as both a request and a response body ends up with two
+// components: the base name (full, readOnly fields included) and
+// "<base>Input" (readOnly fields stripped), so requests can never set a
+// value only the server is allowed to produce.
+func (b *schemaBuilder) structRef(t reflect.Type, input bool) map[string]interface{} {
+	if t.Name() == "" {
+		return b.structBody(t, input)
+	}
+
+	base := b.baseNameFor(t)
+
+	if input {
+		name := base + "Input"
+		if !b.inputEmitted[t] {
+			b.inputEmitted[t] = true
+			b.schemas[name] = b.structBody(t, true)
+		}
+		return map[string]interface{}{"$ref": componentRef(name)}
+	}
+
+	if !b.fullEmitted[t] {
+		b.fullEmitted[t] = true
+		b.schemas[base] = b.structBody(t, false)
+	}
+	return map[string]interface{}{"$ref": componentRef(base)}
+}
+
+// baseNameFor picks (and caches) the component name for t, qualifying it
+// with the package path when another type already claimed the bare type
+// name. The same base name is shared by both the full and "...Input"
+// component variants.
+func (b *schemaBuilder) baseNameFor(t reflect.Type) string {
+	if name, ok := b.baseNames[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if owner, exists := b.nameOwners[name]; exists && owner != t {
+		name = qualifiedTypeName(t)
+	}
+
+	b.baseNames[t] = name
+	b.nameOwners[name] = t
+
+	return name
+}
+
+// qualifiedTypeName renders t's name prefixed with its full package path
+// (with "/" replaced by "."), used to disambiguate two distinct Go types that
+// share a bare type name.
+func qualifiedTypeName(t reflect.Type) string {
+	pkg := strings.ReplaceAll(t.PkgPath(), "/", ".")
+	if pkg == "" {
+		return t.Name()
 	}
+	return pkg + "." + t.Name()
+}
+
+// componentRef builds the "$ref" pointer for a component schema name.
+func componentRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// interfaceSchema builds a oneOf schema for an interface-typed field tagged
+// `openapi:"oneOf=pkg.TypeA|pkg.TypeB,discriminator=kind"`, expanding it to
+// the concrete types registered against that interface via RegisterVariant.
+// The oneOf= type list in the tag is documentation for readers of the
+// struct; resolution always goes through the variant registry, since that's
+// the only place a tag string can be reliably tied back to a reflect.Type.
+func (b *schemaBuilder) interfaceSchema(t reflect.Type, openAPITag string, input bool) map[string]interface{} {
+	variants := variantsFor(t)
+	if len(variants) == 0 {
+		return map[string]interface{}{"type": "object"}
+	}
+
+	oneOf := make([]map[string]interface{}, 0, len(variants))
+	discriminatorProp := tagValue(openAPITag, "discriminator")
+	mapping := make(map[string]string, len(variants))
+
+	for _, v := range variants {
+		ref := b.structRef(v.concreteType, input)
+		oneOf = append(oneOf, ref)
+		if discriminatorProp != "" {
+			mapping[v.discriminatorValue] = ref["$ref"].(string)
+		}
+	}
+
+	schema := map[string]interface{}{"oneOf": oneOf}
 
+	if discriminatorProp != "" {
+		schema["discriminator"] = map[string]interface{}{
+			"propertyName": discriminatorProp,
+			"mapping":      mapping,
+		}
+	}
+
+	return schema
+}
+
+// structBody builds the JSON Schema object describing t's fields. In the
+// input variant, fields tagged `openapi:"readOnly"` are omitted entirely (a
+// client can't set them) and recorded under "x-readOnlyFields" so the
+// validating middleware can reject requests that try to anyway.
+//
+// Anonymously embedded structs are composed via "allOf" rather than
+// flattened: `struct{ BaseResource; Name string }` emits
+// allOf: [{$ref: ".../BaseResource"}, {properties: {name: ...}}], matching
+// how encoding/json itself promotes the embedded type's fields.
+func (b *schemaBuilder) structBody(t reflect.Type, input bool) map[string]interface{} {
+	properties := make(map[string]interface{})
 	required := make([]string, 0)
+	readOnlyFields := make([]string, 0)
+	embedded := make([]map[string]interface{}, 0)
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -62,6 +208,13 @@ func structToSchema(t reflect.Type) map[string]interface{} {
 			continue
 		}
 
+		if field.Anonymous {
+			if embedSchema, ok := b.embeddedSchema(field.Type, input); ok {
+				embedded = append(embedded, embedSchema)
+				continue
+			}
+		}
+
 		// Get JSON tag for field name
 		jsonTag := field.Tag.Get("json")
 		if jsonTag == "" || jsonTag == "-" {
@@ -73,7 +226,20 @@ func structToSchema(t reflect.Type) map[string]interface{} {
 
 		// Get openapi tag for metadata
 		openAPITag := field.Tag.Get("openapi")
-		fieldSchema := typeToSchema(field.Type)
+
+		if hasOpenAPIKeyword(openAPITag, "readOnly") {
+			readOnlyFields = append(readOnlyFields, fieldName)
+			if input {
+				continue
+			}
+		}
+
+		var fieldSchema map[string]interface{}
+		if field.Type.Kind() == reflect.Interface && tagHasKey(openAPITag, "oneOf") {
+			fieldSchema = b.interfaceSchema(field.Type, openAPITag, input)
+		} else {
+			fieldSchema = b.schemaFor(field.Type, input)
+		}
 
 		// Parse openapi tag and enhance schema
 		if openAPITag != "" {
@@ -85,34 +251,140 @@ func structToSchema(t reflect.Type) map[string]interface{} {
 			fieldSchema["description"] = docTag
 		}
 
-		schema["properties"].(map[string]interface{})[fieldName] = fieldSchema
+		properties[fieldName] = fieldSchema
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
 	}
 
 	if len(required) > 0 {
 		schema["required"] = required
 	}
 
-	return schema
+	if input && len(readOnlyFields) > 0 {
+		schema["x-readOnlyFields"] = readOnlyFields
+	}
+
+	if len(embedded) == 0 {
+		return schema
+	}
+
+	return map[string]interface{}{"allOf": append(embedded, schema)}
 }
 
-// arrayToSchema converts array/slice to JSON Schema
-func arrayToSchema(t reflect.Type) map[string]interface{} {
-	itemType := t.Elem()
+// embeddedSchema builds the allOf component for an anonymously embedded
+// field, if it is a struct (directly or via pointer). ok is false for
+// anonymous fields that aren't struct-shaped (e.g. an embedded interface),
+// which are left for the normal field-handling path.
+func (b *schemaBuilder) embeddedSchema(t reflect.Type, input bool) (schema map[string]interface{}, ok bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return b.structRef(t, input), true
+}
+
+// arraySchema converts array/slice to JSON Schema
+func (b *schemaBuilder) arraySchema(t reflect.Type, input bool) map[string]interface{} {
 	return map[string]interface{}{
 		"type":  "array",
-		"items": typeToSchema(itemType),
+		"items": b.schemaFor(t.Elem(), input),
 	}
 }
 
-// mapToSchema converts map to JSON Schema
-func mapToSchema(t reflect.Type) map[string]interface{} {
-	valueType := t.Elem()
+// mapSchema converts map to JSON Schema
+func (b *schemaBuilder) mapSchema(t reflect.Type, input bool) map[string]interface{} {
 	return map[string]interface{}{
 		"type":                 "object",
-		"additionalProperties": typeToSchema(valueType),
+		"additionalProperties": b.schemaFor(t.Elem(), input),
 	}
 }
 
+// hasOpenAPIKeyword reports whether tag contains bare keyword (e.g.
+// "readOnly", "required") as one of its comma-separated parts.
+func hasOpenAPIKeyword(tag, keyword string) bool {
+	for _, part := range strings.Split(tag, ",") {
+		if strings.TrimSpace(part) == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// tagHasKey reports whether tag contains a "key=value" part for key.
+func tagHasKey(tag, key string) bool {
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// tagValue returns the value of a "key=value" part of tag, or "" if absent.
+func tagValue(tag, key string) string {
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == key {
+			return strings.TrimSpace(kv[1])
+		}
+	}
+	return ""
+}
+
+// ParametersFromStruct reflects a struct whose fields carry `in:"query"
+// name:"include"` tags into a slice of Parameter, so services can declare
+// one struct per endpoint the same way they already do for RequestBody.
+// Path parameters are always marked required, per the OpenAPI spec.
+func ParametersFromStruct(v interface{}) []Parameter {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	params := make([]Parameter, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		in := field.Tag.Get("in")
+		if in == "" {
+			continue
+		}
+
+		name := field.Tag.Get("name")
+		if name == "" {
+			name = strings.Split(field.Tag.Get("json"), ",")[0]
+		}
+		if name == "" {
+			continue
+		}
+
+		param := Parameter{
+			Name:        name,
+			In:          in,
+			Required:    in == "path" || hasOpenAPIKeyword(field.Tag.Get("openapi"), "required"),
+			Description: field.Tag.Get("doc"),
+			Schema:      reflect.New(field.Type).Elem().Interface(),
+		}
+
+		params = append(params, param)
+	}
+
+	return params
+}
+
 // parseOpenAPITag parses openapi:"..." tag and enhances schema
 // Format: "required,enum=val1|val2,min=1,max=100,default=50,description=..."
 func parseOpenAPITag(tag string, schema map[string]interface{}, required *[]string, fieldName string) {
@@ -126,6 +398,16 @@ func parseOpenAPITag(tag string, schema map[string]interface{}, required *[]stri
 			continue
 		}
 
+		if part == "readOnly" {
+			schema["readOnly"] = true
+			continue
+		}
+
+		if part == "writeOnly" {
+			schema["writeOnly"] = true
+			continue
+		}
+
 		// Handle key=value pairs
 		kv := strings.SplitN(part, "=", 2)
 		if len(kv) != 2 {
@@ -2,19 +2,81 @@ package apidoc
 
 import (
 	"net/http"
+	"reflect"
 	"sync"
 )
 
 var (
-	registry     = &EndpointRegistry{endpoints: make([]EndpointConfig, 0)}
+	registry = &EndpointRegistry{
+		endpoints:       make([]EndpointConfig, 0),
+		securitySchemes: make(map[string]SecurityScheme),
+		variants:        make(map[reflect.Type][]variant),
+	}
 	registryLock sync.RWMutex
+
+	// variantsLock guards registry.variants separately from registryLock.
+	// Schema building (GenerateOpenAPI/GenerateAPIDescription) holds
+	// registryLock.RLock() for the whole call and, for interface fields,
+	// calls down into variantsFor - sharing registryLock here would make
+	// that a recursive RLock, which sync.RWMutex does not support and which
+	// deadlocks as soon as a writer (e.g. RegisterSecurityScheme) is queued
+	// in between the outer and inner lock attempts.
+	variantsLock sync.RWMutex
 )
 
 // EndpointRegistry stores all registered endpoints
 type EndpointRegistry struct {
-	endpoints []EndpointConfig
-	info      OpenAPIInfo
-	baseURL   string
+	endpoints       []EndpointConfig
+	info            OpenAPIInfo
+	baseURL         string
+	securitySchemes map[string]SecurityScheme
+	variants        map[reflect.Type][]variant // interface type -> its registered concrete implementations
+}
+
+// variant is a single concrete implementation of an interface type,
+// registered via RegisterVariant for oneOf/discriminator schema generation.
+type variant struct {
+	concreteType       reflect.Type
+	discriminatorValue string
+}
+
+// RegisterVariant declares that concrete is a possible value of the
+// interface type pointed to by iface (e.g. RegisterVariant((*Event)(nil),
+// CreatedEvent{}, "created")), so fields of that interface type typed with
+// an `openapi:"oneOf=...,discriminator=kind"` tag can be expanded into a
+// oneOf schema instead of collapsing to a bare object.
+func RegisterVariant(iface interface{}, concrete interface{}, discriminatorValue string) {
+	ifaceType := reflect.TypeOf(iface)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		panic("apidoc: RegisterVariant requires a pointer to the interface type, e.g. (*Event)(nil)")
+	}
+
+	concreteType := reflect.TypeOf(concrete)
+	for concreteType.Kind() == reflect.Ptr {
+		concreteType = concreteType.Elem()
+	}
+
+	variantsLock.Lock()
+	defer variantsLock.Unlock()
+
+	ifaceElem := ifaceType.Elem()
+	registry.variants[ifaceElem] = append(registry.variants[ifaceElem], variant{
+		concreteType:       concreteType,
+		discriminatorValue: discriminatorValue,
+	})
+}
+
+// variantsFor returns the concrete implementations registered for an
+// interface type via RegisterVariant. It is called from inside schema
+// building (see interfaceSchema), which GenerateOpenAPI and
+// GenerateAPIDescription run under registryLock.RLock - variantsLock is a
+// distinct mutex precisely so this doesn't need to recursively re-acquire
+// registryLock.
+func variantsFor(ifaceType reflect.Type) []variant {
+	variantsLock.RLock()
+	defer variantsLock.RUnlock()
+
+	return append([]variant(nil), registry.variants[ifaceType]...)
 }
 
 // SetServiceInfo sets global service metadata
@@ -30,6 +92,17 @@ func SetServiceInfo(title, version, description, baseURL string) {
 	registry.baseURL = baseURL
 }
 
+// RegisterSecurityScheme declares a security scheme beyond the two built-in
+// ones ("mTLS" and "Bearer") that GenerateOpenAPI always emits, e.g. an
+// oauth2 scheme with its scope tables. Registering a scheme under "mTLS" or
+// "Bearer" overrides the built-in definition.
+func RegisterSecurityScheme(name string, scheme SecurityScheme) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	registry.securitySchemes[name] = scheme
+}
+
 // RegisterEndpoint registers an API endpoint with metadata for OpenAPI generation
 // NOTE: This only collects metadata - services must register their own HTTP handlers!
 // This prevents documentation drift by keeping API metadata close to implementation.
@@ -81,14 +154,24 @@ func GenerateOpenAPI() *OpenAPISpec {
 		Description: "JWT Bearer token authentication (ADR-031: OAuth2 scopes validated against AuthorizationElements)",
 	}
 
-	// Convert each endpoint to OpenAPI operation
+	// Schemes registered via RegisterSecurityScheme - e.g. oauth2 flows with
+	// scope tables - are added on top of (and may override) the built-ins.
+	for name, scheme := range registry.securitySchemes {
+		spec.Components.SecuritySchemes[name] = scheme
+	}
+
+	// Convert each endpoint to OpenAPI operation. A single schemaBuilder is
+	// shared across all endpoints so repeated struct types are emitted once
+	// under components.schemas and referenced via "$ref" everywhere else.
+	builder := newSchemaBuilder(spec.Components.Schemas)
+
 	for _, endpoint := range registry.endpoints {
 		pathItem, ok := spec.Paths[endpoint.Path]
 		if !ok {
 			pathItem = PathItem{}
 		}
 
-		operation := endpointToOperation(endpoint, spec.Components.Schemas)
+		operation := endpointToOperation(endpoint, builder)
 
 		// Assign operation to correct method
 		switch endpoint.Method {
@@ -120,8 +203,11 @@ func GenerateAPIDescription() *APIDescription {
 		Version:     registry.info.Version,
 		BaseURL:     registry.baseURL,
 		Endpoints:   make([]APIEndpoint, 0, len(registry.endpoints)),
+		Components:  make(map[string]map[string]interface{}),
 	}
 
+	builder := newSchemaBuilder(desc.Components)
+
 	for _, endpoint := range registry.endpoints {
 		apiEndpoint := APIEndpoint{
 			Method:      endpoint.Method,
@@ -134,7 +220,7 @@ func GenerateAPIDescription() *APIDescription {
 
 		// Convert request body
 		if endpoint.RequestBody != nil {
-			schema := reflectToJSONSchema(endpoint.RequestBody)
+			schema := reflectRequestSchema(endpoint.RequestBody, builder)
 			apiEndpoint.RequestBody = &RequestBodySchema{
 				ContentType: "application/json",
 				Schema:      schema,
@@ -161,7 +247,7 @@ func GenerateAPIDescription() *APIDescription {
 					respSchema.ContentType = "text/plain"
 				} else {
 					// It's a struct → reflect it
-					respSchema.Schema = reflectToJSONSchema(responseType)
+					respSchema.Schema = reflectToJSONSchema(responseType, builder)
 				}
 			}
 
@@ -175,7 +261,7 @@ func GenerateAPIDescription() *APIDescription {
 }
 
 // endpointToOperation converts EndpointConfig to OpenAPI Operation
-func endpointToOperation(endpoint EndpointConfig, schemas map[string]map[string]interface{}) *Operation {
+func endpointToOperation(endpoint EndpointConfig, builder *schemaBuilder) *Operation {
 	op := &Operation{
 		Summary:     endpoint.Summary,
 		Description: endpoint.Description,
@@ -183,9 +269,31 @@ func endpointToOperation(endpoint EndpointConfig, schemas map[string]map[string]
 		Responses:   make(map[string]Response),
 	}
 
+	// Add parameters if present
+	if len(endpoint.Parameters) > 0 {
+		op.Parameters = make([]OpenAPIParameter, len(endpoint.Parameters))
+		for i, param := range endpoint.Parameters {
+			var schema map[string]interface{}
+			if param.Schema != nil {
+				schema = reflectToJSONSchema(param.Schema, builder)
+			}
+
+			op.Parameters[i] = OpenAPIParameter{
+				Name:        param.Name,
+				In:          param.In,
+				Required:    param.Required,
+				Description: param.Description,
+				Schema:      schema,
+				Style:       param.Style,
+				Explode:     param.Explode,
+				Example:     param.Example,
+			}
+		}
+	}
+
 	// Add request body if present
 	if endpoint.RequestBody != nil {
-		schema := reflectToJSONSchema(endpoint.RequestBody)
+		schema := reflectRequestSchema(endpoint.RequestBody, builder)
 		op.RequestBody = &RequestBody{
 			Required: true,
 			Content: map[string]MediaType{
@@ -213,7 +321,7 @@ func endpointToOperation(endpoint EndpointConfig, schemas map[string]map[string]
 				response.Description = desc
 			} else {
 				// It's a struct → reflect it
-				schema := reflectToJSONSchema(responseType)
+				schema := reflectToJSONSchema(responseType, builder)
 				response.Content = map[string]MediaType{
 					"application/json": {
 						Schema: schema,
@@ -225,13 +333,19 @@ func endpointToOperation(endpoint EndpointConfig, schemas map[string]map[string]
 		op.Responses[http.StatusText(statusCode)] = response
 	}
 
-	// Add security if specified
-	if len(endpoint.Security) > 0 {
-		op.Security = make([]map[string][]string, len(endpoint.Security))
-		for i, scheme := range endpoint.Security {
-			op.Security[i] = map[string][]string{
-				scheme: {},
-			}
+	// Add security if specified. OptionalSecurity contributes a leading
+	// empty {} requirement so unauthenticated access is documented alongside
+	// the real alternatives; each entry in endpoint.Security is an
+	// independent (OR'd) requirement, satisfying any one of them suffices.
+	if endpoint.OptionalSecurity || len(endpoint.Security) > 0 {
+		op.Security = make([]map[string][]string, 0, len(endpoint.Security)+1)
+
+		if endpoint.OptionalSecurity {
+			op.Security = append(op.Security, map[string][]string{})
+		}
+
+		for _, requirement := range endpoint.Security {
+			op.Security = append(op.Security, requirement)
 		}
 	}
 
@@ -4,24 +4,45 @@ import "net/http"
 
 // EndpointConfig defines an API endpoint with metadata for automatic OpenAPI generation
 type EndpointConfig struct {
-	Method      string                 // HTTP method: "GET", "POST", "PUT", "DELETE", etc.
-	Path        string                 // URL path: "/api/resource"
-	Handler     http.HandlerFunc       // The actual handler function
-	Summary     string                 // Short one-line description
-	Description string                 // Detailed description (can be multi-line)
-	Tags        []string               // Grouping tags (e.g., ["certificates", "admin"])
-	RequestBody interface{}            // Struct type for request body (will be reflected)
-	Responses   map[int]interface{}    // Status code → response type (struct or string)
-	Security    []string               // Security schemes (e.g., ["mTLS", "Bearer"])
+	Method           string                // HTTP method: "GET", "POST", "PUT", "DELETE", etc.
+	Path             string                // URL path: "/api/resource"
+	Handler          http.HandlerFunc      // The actual handler function
+	Summary          string                // Short one-line description
+	Description      string                // Detailed description (can be multi-line)
+	Tags             []string              // Grouping tags (e.g., ["certificates", "admin"])
+	RequestBody      interface{}           // Struct type for request body (will be reflected)
+	Responses        map[int]interface{}   // Status code → response type (struct or string)
+	Security         []SecurityRequirement // Alternative (OR'd) security requirements; each is an AND of scheme -> required scopes
+	OptionalSecurity bool                  // If true, an empty {} requirement is emitted alongside Security so unauthenticated access is documented
+	Parameters       []Parameter           // Path/query/header/cookie parameters
+}
+
+// SecurityRequirement maps a security scheme name (as registered via
+// RegisterSecurityScheme, or one of the built-in "mTLS"/"Bearer" schemes) to
+// the OAuth2/OIDC scopes required from it. An empty scope slice means the
+// scheme itself is required with no particular scope. EndpointConfig.Security
+// is a list of these requirements; satisfying any one of them is sufficient.
+type SecurityRequirement map[string][]string
+
+// Parameter describes a single path, query, header, or cookie parameter.
+type Parameter struct {
+	Name        string      // Parameter name, e.g. "id" for "/users/{id}"
+	In          string      // "path", "query", "header", or "cookie"
+	Required    bool        // Whether the parameter must be present
+	Description string      // Human-readable description
+	Schema      interface{} // Zero-value instance of the parameter's type (will be reflected)
+	Style       string      // OpenAPI serialization style, e.g. "form", "simple"
+	Explode     bool        // OpenAPI "explode" behavior for array/object values
+	Example     interface{} // Example value
 }
 
 // OpenAPISpec represents a minimal OpenAPI 3.0 specification
 type OpenAPISpec struct {
-	OpenAPI string                `json:"openapi"` // "3.0.0"
-	Info    OpenAPIInfo           `json:"info"`
-	Servers []OpenAPIServer       `json:"servers,omitempty"`
-	Paths   map[string]PathItem   `json:"paths"`
-	Components *OpenAPIComponents `json:"components,omitempty"`
+	OpenAPI    string              `json:"openapi"` // "3.0.0"
+	Info       OpenAPIInfo         `json:"info"`
+	Servers    []OpenAPIServer     `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *OpenAPIComponents  `json:"components,omitempty"`
 }
 
 // OpenAPIInfo contains API metadata
@@ -48,20 +69,33 @@ type PathItem struct {
 
 // Operation describes a single API operation
 type Operation struct {
-	Summary     string                        `json:"summary,omitempty"`
-	Description string                        `json:"description,omitempty"`
-	Tags        []string                      `json:"tags,omitempty"`
-	OperationID string                        `json:"operationId,omitempty"`
-	RequestBody *RequestBody                  `json:"requestBody,omitempty"`
-	Responses   map[string]Response           `json:"responses"`
-	Security    []map[string][]string         `json:"security,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []OpenAPIParameter    `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// OpenAPIParameter describes a single path, query, header, or cookie parameter
+type OpenAPIParameter struct {
+	Name        string                 `json:"name"`
+	In          string                 `json:"in"`
+	Required    bool                   `json:"required,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+	Style       string                 `json:"style,omitempty"`
+	Explode     bool                   `json:"explode,omitempty"`
+	Example     interface{}            `json:"example,omitempty"`
 }
 
 // RequestBody describes request body
 type RequestBody struct {
-	Description string                `json:"description,omitempty"`
-	Required    bool                  `json:"required,omitempty"`
-	Content     map[string]MediaType  `json:"content"`
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
 }
 
 // MediaType describes content type
@@ -72,8 +106,8 @@ type MediaType struct {
 
 // Response describes a single response
 type Response struct {
-	Description string                `json:"description"`
-	Content     map[string]MediaType  `json:"content,omitempty"`
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
 }
 
 // OpenAPIComponents contains reusable schemas
@@ -84,9 +118,28 @@ type OpenAPIComponents struct {
 
 // SecurityScheme describes authentication method
 type SecurityScheme struct {
-	Type        string `json:"type"` // "http", "apiKey", "oauth2", "openIdConnect", "mutualTLS"
-	Scheme      string `json:"scheme,omitempty"` // "bearer", "basic", etc.
-	Description string `json:"description,omitempty"`
+	Type             string      `json:"type"`             // "http", "apiKey", "oauth2", "openIdConnect", "mutualTLS"
+	Scheme           string      `json:"scheme,omitempty"` // "bearer", "basic", etc.
+	Description      string      `json:"description,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`            // required when Type == "oauth2"
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"` // required when Type == "openIdConnect"
+}
+
+// OAuthFlows describes the OAuth2 flows supported by a SecurityScheme. Only
+// the flows a scheme actually offers need to be set.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow describes a single OAuth2 flow and its available scopes.
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
 }
 
 // APIDescription is our internal format for Introspection
@@ -96,6 +149,11 @@ type APIDescription struct {
 	Version     string        `json:"version"`
 	BaseURL     string        `json:"base_url"`
 	Endpoints   []APIEndpoint `json:"endpoints"`
+
+	// Components holds named struct schemas referenced by "$ref" from
+	// Endpoints, mirroring OpenAPISpec.Components so introspection
+	// consumers can resolve them the same way OpenAPI clients do.
+	Components map[string]map[string]interface{} `json:"components,omitempty"`
 }
 
 // APIEndpoint describes a single endpoint (internal format)
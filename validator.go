@@ -0,0 +1,407 @@
+package apidoc
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidationError describes a single schema violation.
+type ValidationError struct {
+	Path    string `json:"path"`    // JSON path to the offending value, e.g. "$.user.email"
+	Rule    string `json:"rule"`    // schema keyword that was violated, e.g. "required", "format"
+	Message string `json:"message"` // human-readable explanation
+}
+
+// ValidationErrors aggregates every violation found for a single document,
+// so callers can surface the full list instead of only the first failure.
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	if len(errs) == 0 {
+		return "validation failed"
+	}
+	parts := make([]string, len(errs))
+	for i, e := range errs {
+		parts[i] = fmt.Sprintf("%s: %s (%s)", e.Path, e.Message, e.Rule)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validation direction, used to decide which of the readOnly/writeOnly
+// checks in validateAgainstSchema apply: a request can legitimately set a
+// writeOnly field (e.g. a password on create) but never a readOnly one, and
+// a response is the exact opposite.
+const (
+	directionRequest  = "request"
+	directionResponse = "response"
+)
+
+// validateAgainstSchema walks a JSON Schema map (as produced by
+// reflectToJSONSchema/reflectRequestSchema) and checks data against it,
+// resolving "$ref" entries against schemas. It never stops at the first
+// violation - every failure found is appended to the returned slice.
+func validateAgainstSchema(schema map[string]interface{}, schemas map[string]map[string]interface{}, data interface{}, path string, direction string) ValidationErrors {
+	var errs ValidationErrors
+
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, ok := schemas[refName(ref)]
+		if !ok {
+			return ValidationErrors{{Path: path, Rule: "$ref", Message: fmt.Sprintf("unresolved schema reference %q", ref)}}
+		}
+		return validateAgainstSchema(resolved, schemas, data, path, direction)
+	}
+
+	if allOf, ok := schema["allOf"].([]map[string]interface{}); ok {
+		for _, sub := range allOf {
+			errs = append(errs, validateAgainstSchema(sub, schemas, data, path, direction)...)
+		}
+		return errs
+	}
+
+	if oneOf, ok := schema["oneOf"].([]map[string]interface{}); ok {
+		return validateOneOf(schema, oneOf, schemas, data, path, direction)
+	}
+
+	if data == nil {
+		// Absence is only an error when the field is listed in a parent's
+		// "required" - a bare null value against an optional field is fine.
+		return errs
+	}
+
+	typ, _ := schema["type"].(string)
+
+	switch typ {
+	case "object":
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected an object"}}
+		}
+
+		for _, name := range requiredFields(schema) {
+			if _, present := obj[name]; !present {
+				errs = append(errs, ValidationError{Path: path, Rule: "required", Message: fmt.Sprintf("missing required field %q", name)})
+			}
+		}
+
+		if direction == directionRequest {
+			errs = append(errs, rejectReadOnlyWrites(schema, obj, path)...)
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, value := range obj {
+			propSchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				continue // additional properties are allowed unless the schema says otherwise
+			}
+
+			if direction == directionResponse && isWriteOnly(propSchema) {
+				errs = append(errs, ValidationError{Path: path + "." + name, Rule: "writeOnly", Message: fmt.Sprintf("field %q is write-only and must not appear in responses", name)})
+				continue
+			}
+
+			errs = append(errs, validateAgainstSchema(propSchema, schemas, value, path+"."+name, direction)...)
+		}
+
+	case "array":
+		arr, ok := data.([]interface{})
+		if !ok {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected an array"}}
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range arr {
+			errs = append(errs, validateAgainstSchema(items, schemas, item, fmt.Sprintf("%s[%d]", path, i), direction)...)
+		}
+
+	case "string":
+		str, ok := data.(string)
+		if !ok {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected a string"}}
+		}
+		errs = append(errs, validateString(schema, str, path)...)
+
+	case "integer":
+		num, ok := data.(float64)
+		if !ok {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected an integer"}}
+		}
+		if num != float64(int64(num)) {
+			errs = append(errs, ValidationError{Path: path, Rule: "type", Message: "expected an integer"})
+		}
+		errs = append(errs, validateNumberRange(schema, num, path)...)
+
+	case "number":
+		num, ok := data.(float64)
+		if !ok {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected a number"}}
+		}
+		errs = append(errs, validateNumberRange(schema, num, path)...)
+
+	case "boolean":
+		if _, ok := data.(bool); !ok {
+			errs = append(errs, ValidationError{Path: path, Rule: "type", Message: "expected a boolean"})
+		}
+	}
+
+	if enumValues, ok := schema["enum"].([]string); ok && len(enumValues) > 0 {
+		if !matchesEnum(enumValues, data) {
+			errs = append(errs, ValidationError{Path: path, Rule: "enum", Message: fmt.Sprintf("value must be one of %s", strings.Join(enumValues, ", "))})
+		}
+	}
+
+	return errs
+}
+
+// validateOneOf validates data against a polymorphic oneOf schema (built by
+// schemaBuilder.interfaceSchema for an `openapi:"oneOf=...,discriminator=..."`
+// field). When the schema carries a discriminator, the discriminator
+// property picks the exact variant to validate against; without one, data is
+// accepted as soon as it cleanly matches any single variant.
+func validateOneOf(schema map[string]interface{}, oneOf []map[string]interface{}, schemas map[string]map[string]interface{}, data interface{}, path, direction string) ValidationErrors {
+	if data == nil {
+		return nil
+	}
+
+	if disc, ok := schema["discriminator"].(map[string]interface{}); ok {
+		propertyName, _ := disc["propertyName"].(string)
+		mapping, _ := disc["mapping"].(map[string]string)
+
+		if propertyName != "" {
+			obj, ok := data.(map[string]interface{})
+			if !ok {
+				return ValidationErrors{{Path: path, Rule: "type", Message: "expected an object"}}
+			}
+
+			value, _ := obj[propertyName].(string)
+			ref, ok := mapping[value]
+			if !ok {
+				return ValidationErrors{{Path: path, Rule: "discriminator", Message: fmt.Sprintf("unrecognized %q value %q", propertyName, value)}}
+			}
+
+			resolved, ok := schemas[refName(ref)]
+			if !ok {
+				return ValidationErrors{{Path: path, Rule: "$ref", Message: fmt.Sprintf("unresolved schema reference %q", ref)}}
+			}
+			return validateAgainstSchema(resolved, schemas, data, path, direction)
+		}
+	}
+
+	// No discriminator to route on - fall back to accepting data as soon as
+	// it cleanly matches any one of the variants. Data that matches more than
+	// one variant is accepted as well.
+	for _, sub := range oneOf {
+		if len(validateAgainstSchema(sub, schemas, data, path, direction)) == 0 {
+			return nil
+		}
+	}
+	return ValidationErrors{{Path: path, Rule: "oneOf", Message: "value does not match any of the allowed schemas"}}
+}
+
+func requiredFields(schema map[string]interface{}) []string {
+	if fields, ok := schema["required"].([]string); ok {
+		return fields
+	}
+	return nil
+}
+
+// rejectReadOnlyWrites flags request properties that try to set a field the
+// "...Input" schema stripped as readOnly (see schemaBuilder.structBody).
+func rejectReadOnlyWrites(schema map[string]interface{}, obj map[string]interface{}, path string) ValidationErrors {
+	fields, ok := schema["x-readOnlyFields"].([]string)
+	if !ok {
+		return nil
+	}
+
+	var errs ValidationErrors
+	for _, name := range fields {
+		if _, present := obj[name]; present {
+			errs = append(errs, ValidationError{Path: path + "." + name, Rule: "readOnly", Message: fmt.Sprintf("field %q is read-only and cannot be set", name)})
+		}
+	}
+	return errs
+}
+
+func isWriteOnly(propSchema map[string]interface{}) bool {
+	writeOnly, _ := propSchema["writeOnly"].(bool)
+	return writeOnly
+}
+
+func matchesEnum(values []string, data interface{}) bool {
+	actual := fmt.Sprintf("%v", data)
+	for _, v := range values {
+		if v == actual {
+			return true
+		}
+	}
+	return false
+}
+
+func validateNumberRange(schema map[string]interface{}, num float64, path string) ValidationErrors {
+	var errs ValidationErrors
+
+	if min, ok := toFloat(schema["minimum"]); ok && num < min {
+		errs = append(errs, ValidationError{Path: path, Rule: "minimum", Message: fmt.Sprintf("value %v is below minimum %v", num, min)})
+	}
+	if max, ok := toFloat(schema["maximum"]); ok && num > max {
+		errs = append(errs, ValidationError{Path: path, Rule: "maximum", Message: fmt.Sprintf("value %v is above maximum %v", num, max)})
+	}
+
+	return errs
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func validateString(schema map[string]interface{}, str string, path string) ValidationErrors {
+	var errs ValidationErrors
+
+	if minLen, ok := toFloat(schema["minLength"]); ok && len([]rune(str)) < int(minLen) {
+		errs = append(errs, ValidationError{Path: path, Rule: "minLength", Message: fmt.Sprintf("length is below minimum %v", minLen)})
+	}
+	if maxLen, ok := toFloat(schema["maxLength"]); ok && len([]rune(str)) > int(maxLen) {
+		errs = append(errs, ValidationError{Path: path, Rule: "maxLength", Message: fmt.Sprintf("length is above maximum %v", maxLen)})
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok && pattern != "" {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			errs = append(errs, ValidationError{Path: path, Rule: "pattern", Message: fmt.Sprintf("invalid pattern %q: %v", pattern, err)})
+		} else if !re.MatchString(str) {
+			errs = append(errs, ValidationError{Path: path, Rule: "pattern", Message: fmt.Sprintf("value does not match pattern %q", pattern)})
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && format != "" {
+		if msg, valid := validateFormat(format, str); !valid {
+			errs = append(errs, ValidationError{Path: path, Rule: "format", Message: msg})
+		}
+	}
+
+	return errs
+}
+
+var (
+	patternCacheLock sync.Mutex
+	patternCache     = make(map[string]*regexp.Regexp)
+)
+
+// compilePattern compiles and caches a regex pattern, since the same pattern
+// is typically reused across many requests for the same field.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	patternCacheLock.Lock()
+	defer patternCacheLock.Unlock()
+
+	if re, ok := patternCache[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	patternCache[pattern] = re
+	return re, nil
+}
+
+var (
+	emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// validateFormat checks str against a well-known "format" hint. Unrecognized
+// formats are treated as informational only and always pass, matching how
+// JSON Schema implementations typically handle unknown format values.
+func validateFormat(format, str string) (message string, valid bool) {
+	switch format {
+	case "email":
+		if !emailPattern.MatchString(str) {
+			return "value is not a valid email address", false
+		}
+	case "uri":
+		u, err := url.Parse(str)
+		if err != nil || u.Scheme == "" {
+			return "value is not a valid URI", false
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(str) {
+			return "value is not a valid UUID", false
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			return "value is not a valid RFC3339 date-time", false
+		}
+	case "ipv4":
+		ip := net.ParseIP(str)
+		if ip == nil || ip.To4() == nil {
+			return "value is not a valid IPv4 address", false
+		}
+	case "ipv6":
+		ip := net.ParseIP(str)
+		if ip == nil || ip.To4() != nil || !strings.Contains(str, ":") {
+			return "value is not a valid IPv6 address", false
+		}
+	}
+
+	return "", true
+}
+
+// validateParameterValue checks a raw string parameter value (as pulled from
+// a URL path, query string, header, or cookie) against schema, converting it
+// to the schema's declared type first.
+func validateParameterValue(schema map[string]interface{}, raw string, path string) ValidationErrors {
+	if schema == nil {
+		return nil
+	}
+
+	typ, _ := schema["type"].(string)
+
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected an integer"}}
+		}
+		return validateNumberRange(schema, float64(n), path)
+
+	case "number":
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected a number"}}
+		}
+		return validateNumberRange(schema, f, path)
+
+	case "boolean":
+		if _, err := strconv.ParseBool(raw); err != nil {
+			return ValidationErrors{{Path: path, Rule: "type", Message: "expected a boolean"}}
+		}
+		return nil
+
+	default:
+		errs := validateString(schema, raw, path)
+		if enumValues, ok := schema["enum"].([]string); ok && len(enumValues) > 0 && !matchesEnum(enumValues, raw) {
+			errs = append(errs, ValidationError{Path: path, Rule: "enum", Message: fmt.Sprintf("value must be one of %s", strings.Join(enumValues, ", "))})
+		}
+		return errs
+	}
+}
+
+// refName extracts the component name from a "#/components/schemas/Name"
+// reference produced by componentRef.
+func refName(ref string) string {
+	const prefix = "#/components/schemas/"
+	return strings.TrimPrefix(ref, prefix)
+}